@@ -2,12 +2,19 @@
 //
 // The focus is on usefulness rather than set theory.
 //
-// Note that while this package provides two distinct set types, their APIs
-// disagree in favor of efficiency.
+// Note that while this package provides several distinct set types, their
+// APIs disagree in favor of efficiency.
 //
 package contains
 
-import "math/bits"
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
 
 const (
 	// minDiff is the minimum number of bits a new key must add to be added
@@ -19,21 +26,85 @@ const (
 
 // A Set is a collection of sparse keys. The zero value is ready to use.
 type Set struct {
+	// nContains, nAdd, nFilterMatch, nFalsePos, and metrics are accessed
+	// atomically and must stay first in the struct so that they are
+	// 64-bit aligned on 32-bit architectures.
+	nContains, nAdd, nFilterMatch, nFalsePos uint64
+	metrics                                  uint32
+
 	filters []uintptr
 	keys    [][]uintptr
 }
 
+// EnableMetrics turns on instrumentation for the set, so that Metrics
+// reports the effectiveness of its bloom filters. This adds an atomic
+// increment to every Contains and Add call, so leave it disabled unless
+// you need to observe filter behavior.
+func (s *Set) EnableMetrics() {
+	atomic.StoreUint32(&s.metrics, 1)
+}
+
+// Metrics reports instrumentation for a Set. The call counters (Contains,
+// Add, FilterMatches, FalsePositives) count only calls made while metrics
+// are enabled with EnableMetrics, and Reset does not clear them. Buckets,
+// Keys, and AvgBucketLen describe the set's current contents and are
+// reported whether or not metrics are enabled.
+type Metrics struct {
+	// Contains and Add are the number of calls to the respective methods.
+	Contains, Add uint64
+	// FilterMatches is the number of times a bucket's filter matched the key
+	// being looked up or added. A single call can match several buckets.
+	FilterMatches uint64
+	// FalsePositives is the number of filter matches where the bucket's key
+	// list did not actually contain the key.
+	FalsePositives uint64
+	// Buckets is the number of buckets currently in the set.
+	Buckets int
+	// Keys is the number of keys currently in the set.
+	Keys int
+	// AvgBucketLen is Keys divided by Buckets.
+	AvgBucketLen float64
+}
+
+// Metrics returns the set's recorded metrics. See EnableMetrics.
+func (s *Set) Metrics() Metrics {
+	m := Metrics{
+		Contains:       atomic.LoadUint64(&s.nContains),
+		Add:            atomic.LoadUint64(&s.nAdd),
+		FilterMatches:  atomic.LoadUint64(&s.nFilterMatch),
+		FalsePositives: atomic.LoadUint64(&s.nFalsePos),
+		Buckets:        len(s.filters),
+	}
+	for _, l := range s.keys {
+		m.Keys += len(l)
+	}
+	if m.Buckets > 0 {
+		m.AvgBucketLen = float64(m.Keys) / float64(m.Buckets)
+	}
+	return m
+}
+
 // Add adds the key to the set. Returns true if the key is new or false if the
 // key was already present.
 func (s *Set) Add(key uintptr) bool {
+	metrics := atomic.LoadUint32(&s.metrics) != 0
+	if metrics {
+		atomic.AddUint64(&s.nAdd, 1)
+	}
 	r := filter(key)
 	for k, f := range s.filters {
 		if f&r == r {
+			if metrics {
+				atomic.AddUint64(&s.nFilterMatch, 1)
+			}
 			for _, v := range s.keys[k] {
 				if v == key {
 					return false
 				}
 			}
+			if metrics {
+				atomic.AddUint64(&s.nFalsePos, 1)
+			}
 			// If the key is already present in a filter but not in the
 			// associated list, we should add it to that list, so that further
 			// checks will find it there.
@@ -62,19 +133,102 @@ func (s *Set) Add(key uintptr) bool {
 
 // Contains returns true if key exists in the set.
 func (s *Set) Contains(key uintptr) bool {
+	metrics := atomic.LoadUint32(&s.metrics) != 0
+	if metrics {
+		atomic.AddUint64(&s.nContains, 1)
+	}
 	r := filter(key)
 	for k, f := range s.filters {
 		if f&r == r {
+			if metrics {
+				atomic.AddUint64(&s.nFilterMatch, 1)
+			}
 			for _, v := range s.keys[k] {
 				if v == key {
 					return true
 				}
 			}
+			if metrics {
+				atomic.AddUint64(&s.nFalsePos, 1)
+			}
 		}
 	}
 	return false
 }
 
+// Remove removes a key from the set. Returns true if the key was present or
+// false if it was not. The bloom filter for the key's bucket is left
+// untouched, so it may continue to report matches for removed keys; this
+// only costs a wasted linear scan, never a wrong answer.
+func (s *Set) Remove(key uintptr) bool {
+	r := filter(key)
+	for k, f := range s.filters {
+		if f&r == r {
+			for i, v := range s.keys[k] {
+				if v == key {
+					s.keys[k] = append(s.keys[k][:i], s.keys[k][i+1:]...)
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// Min returns the smallest key in the set, or false if the set is empty.
+func (s *Set) Min() (uintptr, bool) {
+	var min uintptr
+	found := false
+	for _, l := range s.keys {
+		for _, key := range l {
+			if !found || key < min {
+				min = key
+				found = true
+			}
+		}
+	}
+	return min, found
+}
+
+// Max returns the largest key in the set, or false if the set is empty.
+func (s *Set) Max() (uintptr, bool) {
+	var max uintptr
+	found := false
+	for _, l := range s.keys {
+		for _, key := range l {
+			if !found || key > max {
+				max = key
+				found = true
+			}
+		}
+	}
+	return max, found
+}
+
+// TakeMin removes and returns the smallest key in the set, or returns false
+// if the set is empty.
+func (s *Set) TakeMin() (uintptr, bool) {
+	key, ok := s.Min()
+	if !ok {
+		return 0, false
+	}
+	s.Remove(key)
+	return key, true
+}
+
+// Do calls f for each key in the set, in ascending order, until f returns
+// false. Since buckets aren't kept in key order, Do sorts a scratch copy of
+// the set's keys first.
+func (s *Set) Do(f func(uintptr) bool) {
+	keys := s.Keys()
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	for _, key := range keys {
+		if !f(key) {
+			return
+		}
+	}
+}
+
 // Keys returns a slice containing all keys in the set. Returns nil if the set
 // is empty.
 func (s *Set) Keys() []uintptr {
@@ -87,6 +241,110 @@ func (s *Set) Keys() []uintptr {
 	return r
 }
 
+// Len returns the number of keys in the set.
+func (s *Set) Len() int {
+	n := 0
+	for _, l := range s.keys {
+		n += len(l)
+	}
+	return n
+}
+
+// IsEmpty returns true if the set has no keys.
+func (s *Set) IsEmpty() bool {
+	for _, l := range s.keys {
+		if len(l) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// UnionWith adds every key in o to s, so that s becomes the union of the
+// two sets.
+func (s *Set) UnionWith(o *Set) {
+	for _, l := range o.keys {
+		for _, key := range l {
+			s.Add(key)
+		}
+	}
+}
+
+// IntersectionWith sets s to the intersection of s and o, rebuilding s's
+// filters from the surviving keys.
+func (s *Set) IntersectionWith(o *Set) {
+	var keep []uintptr
+	for _, l := range s.keys {
+		for _, key := range l {
+			if o.Contains(key) {
+				keep = append(keep, key)
+			}
+		}
+	}
+	s.Reset()
+	for _, key := range keep {
+		s.Add(key)
+	}
+}
+
+// DifferenceWith sets s to the elements of s that are not in o, rebuilding
+// s's filters from the surviving keys.
+func (s *Set) DifferenceWith(o *Set) {
+	var keep []uintptr
+	for _, l := range s.keys {
+		for _, key := range l {
+			if !o.Contains(key) {
+				keep = append(keep, key)
+			}
+		}
+	}
+	s.Reset()
+	for _, key := range keep {
+		s.Add(key)
+	}
+}
+
+// SymmetricDifferenceWith sets s to the keys that are in exactly one of s
+// and o, rebuilding s's filters from the result.
+func (s *Set) SymmetricDifferenceWith(o *Set) {
+	var keep []uintptr
+	for _, l := range s.keys {
+		for _, key := range l {
+			if !o.Contains(key) {
+				keep = append(keep, key)
+			}
+		}
+	}
+	for _, l := range o.keys {
+		for _, key := range l {
+			if !s.Contains(key) {
+				keep = append(keep, key)
+			}
+		}
+	}
+	s.Reset()
+	for _, key := range keep {
+		s.Add(key)
+	}
+}
+
+// SubsetOf returns true if every key in s is also in o.
+func (s *Set) SubsetOf(o *Set) bool {
+	for _, l := range s.keys {
+		for _, key := range l {
+			if !o.Contains(key) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Equals returns true if s and o contain the same keys.
+func (s *Set) Equals(o *Set) bool {
+	return s.Len() == o.Len() && s.SubsetOf(o)
+}
+
 // Reset removes all objects from the set. Reusing the set after calling Reset
 // allows the previously allocated memory to be reused.
 func (s *Set) Reset() {
@@ -101,6 +359,48 @@ func (s *Set) Reset() {
 	}
 }
 
+// String returns a human-readable representation of the set, e.g.
+// "{1 3 435}".
+func (s *Set) String() string {
+	var b strings.Builder
+	b.WriteByte('{')
+	first := true
+	s.Do(func(key uintptr) bool {
+		if !first {
+			b.WriteByte(' ')
+		}
+		first = false
+		fmt.Fprint(&b, key)
+		return true
+	})
+	b.WriteByte('}')
+	return b.String()
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The encoding is a
+// sequence of 64-bit little-endian keys; it omits the bloom filters, which
+// UnmarshalBinary rebuilds by re-adding each key.
+func (s *Set) MarshalBinary() ([]byte, error) {
+	keys := s.Keys()
+	b := make([]byte, len(keys)*8)
+	for i, key := range keys {
+		binary.LittleEndian.PutUint64(b[i*8:], uint64(key))
+	}
+	return b, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (s *Set) UnmarshalBinary(data []byte) error {
+	if len(data)%8 != 0 {
+		return fmt.Errorf("contains: invalid Set encoding: length not a multiple of 8")
+	}
+	s.Reset()
+	for i := 0; i < len(data); i += 8 {
+		s.Add(uintptr(binary.LittleEndian.Uint64(data[i:])))
+	}
+	return nil
+}
+
 func filter(key uintptr) uintptr {
 	if ^uintptr(0) != 0xffffffff {
 		// 64-bit; use Knuth's MMIX LCG. We have to "convert" to uint64 because
@@ -147,6 +147,347 @@ func TestReset(t *testing.T) {
 	}
 }
 
+// TestLen tests that Len reports the number of keys added.
+func TestLen(t *testing.T) {
+	s := Set{}
+	for x := 0; x < testN; x++ {
+		if s.Len() != x {
+			t.Errorf("wrong length: want %d, have %d", x, s.Len())
+		}
+		s.Add(uintptr(x))
+	}
+	if s.Len() != testN {
+		t.Errorf("wrong length: want %d, have %d", testN, s.Len())
+	}
+}
+
+// TestIsEmpty tests that IsEmpty reports whether the set has keys.
+func TestIsEmpty(t *testing.T) {
+	s := Set{}
+	if !s.IsEmpty() {
+		t.Errorf("zero value isn't empty")
+	}
+	s.Add(1)
+	if s.IsEmpty() {
+		t.Errorf("set with a key reports empty")
+	}
+	s.Reset()
+	if !s.IsEmpty() {
+		t.Errorf("reset set isn't empty")
+	}
+}
+
+// TestUnionWith tests that UnionWith computes a correct union.
+func TestUnionWith(t *testing.T) {
+	var a, b Set
+	for x := 0; x < testN; x += 2 {
+		a.Add(uintptr(x))
+	}
+	for x := 1; x < testN; x += 2 {
+		b.Add(uintptr(x))
+	}
+	a.UnionWith(&b)
+	for x := 0; x < testN; x++ {
+		if !a.Contains(uintptr(x)) {
+			t.Errorf("union missing key %d", x)
+		}
+	}
+}
+
+// TestIntersectionWith tests that IntersectionWith computes a correct
+// intersection.
+func TestIntersectionWith(t *testing.T) {
+	var a, b Set
+	for x := 0; x < testN; x++ {
+		a.Add(uintptr(x))
+	}
+	for x := 0; x < testN; x += 2 {
+		b.Add(uintptr(x))
+	}
+	a.IntersectionWith(&b)
+	for x := 0; x < testN; x++ {
+		want := x%2 == 0
+		if a.Contains(uintptr(x)) != want {
+			t.Errorf("wrong intersection at key %d: want %v, have %v", x, want, a.Contains(uintptr(x)))
+		}
+	}
+}
+
+// TestDifferenceWith tests that DifferenceWith computes a correct
+// difference.
+func TestDifferenceWith(t *testing.T) {
+	var a, b Set
+	for x := 0; x < testN; x++ {
+		a.Add(uintptr(x))
+	}
+	for x := 0; x < testN; x += 2 {
+		b.Add(uintptr(x))
+	}
+	a.DifferenceWith(&b)
+	for x := 0; x < testN; x++ {
+		want := x%2 != 0
+		if a.Contains(uintptr(x)) != want {
+			t.Errorf("wrong difference at key %d: want %v, have %v", x, want, a.Contains(uintptr(x)))
+		}
+	}
+}
+
+// TestSymmetricDifferenceWith tests that SymmetricDifferenceWith computes a
+// correct symmetric difference.
+func TestSymmetricDifferenceWith(t *testing.T) {
+	var a, b Set
+	for x := 0; x < testN; x++ {
+		a.Add(uintptr(x))
+	}
+	for x := testN / 2; x < testN+testN/2; x++ {
+		b.Add(uintptr(x))
+	}
+	a.SymmetricDifferenceWith(&b)
+	for x := 0; x < testN+testN/2; x++ {
+		want := x < testN/2 || x >= testN
+		if a.Contains(uintptr(x)) != want {
+			t.Errorf("wrong symmetric difference at key %d: want %v, have %v", x, want, a.Contains(uintptr(x)))
+		}
+	}
+}
+
+// TestSubsetOf tests that SubsetOf reports correct subset relations.
+func TestSubsetOf(t *testing.T) {
+	var a, b Set
+	for x := 0; x < testN; x += 2 {
+		a.Add(uintptr(x))
+		b.Add(uintptr(x))
+	}
+	b.Add(1)
+	if !a.SubsetOf(&b) {
+		t.Errorf("a should be a subset of b")
+	}
+	if b.SubsetOf(&a) {
+		t.Errorf("b should not be a subset of a")
+	}
+}
+
+// TestEquals tests that Equals reports correct equality.
+func TestEquals(t *testing.T) {
+	var a, b Set
+	for x := 0; x < testN; x++ {
+		a.Add(uintptr(x))
+		b.Add(uintptr(x))
+	}
+	if !a.Equals(&b) {
+		t.Errorf("equal sets should be equal")
+	}
+	b.Add(testN)
+	if a.Equals(&b) {
+		t.Errorf("sets should not be equal")
+	}
+}
+
+// TestRemove tests that Remove drops a key and reports whether it was
+// present.
+func TestRemove(t *testing.T) {
+	s := Set{}
+	for x := 0; x < testN; x++ {
+		s.Add(uintptr(x))
+	}
+	for x := 0; x < testN; x += 2 {
+		if !s.Remove(uintptr(x)) {
+			t.Errorf("couldn't remove present key %d", x)
+		}
+		if s.Remove(uintptr(x)) {
+			t.Errorf("removed already-removed key %d", x)
+		}
+	}
+	for x := 0; x < testN; x++ {
+		want := x%2 != 0
+		if s.Contains(uintptr(x)) != want {
+			t.Errorf("wrong membership at key %d: want %v, have %v", x, want, s.Contains(uintptr(x)))
+		}
+	}
+	if (&Set{}).Remove(1) {
+		t.Errorf("removed key from empty set")
+	}
+}
+
+// TestMinMax tests that Min and Max report the smallest and largest keys in
+// the set.
+func TestMinMax(t *testing.T) {
+	s := Set{}
+	if _, ok := s.Min(); ok {
+		t.Errorf("empty set has a Min")
+	}
+	if _, ok := s.Max(); ok {
+		t.Errorf("empty set has a Max")
+	}
+	s.Add(5)
+	s.Add(1)
+	s.Add(testN - 1)
+	if min, ok := s.Min(); !ok || min != 1 {
+		t.Errorf("wrong Min: want 1, have %d, ok %v", min, ok)
+	}
+	if max, ok := s.Max(); !ok || max != testN-1 {
+		t.Errorf("wrong Max: want %d, have %d, ok %v", testN-1, max, ok)
+	}
+}
+
+// TestTakeMin tests that TakeMin drains keys in ascending order.
+func TestTakeMin(t *testing.T) {
+	s := Set{}
+	for x := 0; x < testN; x++ {
+		s.Add(uintptr(x))
+	}
+	for x := 0; x < testN; x++ {
+		key, ok := s.TakeMin()
+		if !ok || key != uintptr(x) {
+			t.Errorf("wrong TakeMin: want %d, have %d, ok %v", x, key, ok)
+		}
+	}
+	if _, ok := s.TakeMin(); ok {
+		t.Errorf("drained set still has a TakeMin")
+	}
+}
+
+// TestDo tests that Do visits every key in ascending order and can stop
+// early.
+func TestDo(t *testing.T) {
+	s := Set{}
+	for x := 0; x < testN; x++ {
+		s.Add(uintptr(x))
+	}
+	var got []uintptr
+	s.Do(func(key uintptr) bool {
+		got = append(got, key)
+		return true
+	})
+	if len(got) != testN {
+		t.Fatalf("wrong number of visited keys: want %d, have %d", testN, len(got))
+	}
+	for i, x := range got {
+		if x != uintptr(i) {
+			t.Errorf("wrong key at position %d: want %d, have %d", i, i, x)
+		}
+	}
+	n := 0
+	s.Do(func(key uintptr) bool {
+		n++
+		return key < testN/2
+	})
+	if n != testN/2+1 {
+		t.Errorf("wrong number of keys visited before stopping: want %d, have %d", testN/2+1, n)
+	}
+}
+
+// TestString tests that String renders a set's keys in ascending order.
+func TestString(t *testing.T) {
+	s := Set{}
+	if s.String() != "{}" {
+		t.Errorf("wrong string for empty set: %q", s.String())
+	}
+	s.Add(3)
+	s.Add(1)
+	s.Add(435)
+	if want := "{1 3 435}"; s.String() != want {
+		t.Errorf("wrong string: want %q, have %q", want, s.String())
+	}
+}
+
+// TestMarshalBinary tests that a Set round-trips through MarshalBinary and
+// UnmarshalBinary.
+func TestMarshalBinary(t *testing.T) {
+	var a Set
+	for x := 0; x < testN; x += 3 {
+		a.Add(uintptr(x))
+	}
+	b, err := a.MarshalBinary()
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	var c Set
+	if err := c.UnmarshalBinary(b); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if !a.Equals(&c) {
+		t.Errorf("round trip changed the set")
+	}
+}
+
+// TestUnmarshalBinaryInvalid tests that UnmarshalBinary rejects data whose
+// length isn't a multiple of the key encoding width.
+func TestUnmarshalBinaryInvalid(t *testing.T) {
+	var a Set
+	for x := 0; x < testN; x += 3 {
+		a.Add(uintptr(x))
+	}
+	b, err := a.MarshalBinary()
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	t.Run("truncated", func(t *testing.T) {
+		var c Set
+		if err := c.UnmarshalBinary(b[:len(b)-1]); err == nil {
+			t.Errorf("accepted data with a truncated trailing key")
+		}
+	})
+	t.Run("too_short", func(t *testing.T) {
+		var c Set
+		if err := c.UnmarshalBinary([]byte{1, 2, 3}); err == nil {
+			t.Errorf("accepted data shorter than a single key")
+		}
+	})
+}
+
+// TestMetrics tests that metrics are only recorded once EnableMetrics has
+// been called, and that the counts they report make sense.
+func TestMetrics(t *testing.T) {
+	s := Set{}
+	s.Add(1)
+	s.Contains(1)
+	if m := s.Metrics(); m.Add != 0 || m.Contains != 0 {
+		t.Errorf("metrics recorded before EnableMetrics: %+v", m)
+	}
+	s.EnableMetrics()
+	for x := 0; x < testN; x++ {
+		s.Add(uintptr(x))
+	}
+	for x := 0; x < testN; x++ {
+		s.Contains(uintptr(x))
+	}
+	m := s.Metrics()
+	if m.Add != testN {
+		t.Errorf("wrong Add count: want %d, have %d", testN, m.Add)
+	}
+	if m.Contains != testN {
+		t.Errorf("wrong Contains count: want %d, have %d", testN, m.Contains)
+	}
+	if m.Keys != testN {
+		t.Errorf("wrong Keys count: want %d, have %d", testN, m.Keys)
+	}
+	if m.Buckets != len(s.filters) {
+		t.Errorf("wrong Buckets count: want %d, have %d", len(s.filters), m.Buckets)
+	}
+	if m.AvgBucketLen != float64(m.Keys)/float64(m.Buckets) {
+		t.Errorf("wrong AvgBucketLen: want %v, have %v", float64(m.Keys)/float64(m.Buckets), m.AvgBucketLen)
+	}
+
+	// Force a filter match that isn't a true hit, by widening a bucket's
+	// filter to match everything, to drive FilterMatches and
+	// FalsePositives specifically.
+	fp := Set{}
+	fp.EnableMetrics()
+	fp.Add(1)
+	fp.filters[0] = ^uintptr(0)
+	if fp.Contains(2) {
+		t.Fatalf("forced filter match incorrectly found key 2")
+	}
+	fm := fp.Metrics()
+	if fm.FilterMatches != 1 {
+		t.Errorf("wrong FilterMatches: want 1, have %d", fm.FilterMatches)
+	}
+	if fm.FalsePositives != 1 {
+		t.Errorf("wrong FalsePositives: want 1, have %d", fm.FalsePositives)
+	}
+}
+
 // BenchmarkContains benchmarks finding keys in a Set.
 func BenchmarkContains(b *testing.B) {
 	cases := []int{1 << 0, 1 << 2, 1 << 3, 1 << 6, 1 << 12, 1 << 16}
@@ -1,6 +1,11 @@
 package contains
 
-import "math/bits"
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"strings"
+)
 
 // Dense is a set of integers backed by a single contiguous block of
 // memory beginning at key 0. The zero value is ready to use.
@@ -10,6 +15,10 @@ type Dense struct {
 
 const wordSize = bits.UintSize
 
+// wordBytes is the number of bytes in a word, used to size the binary
+// encoding.
+const wordBytes = wordSize / 8
+
 // Add adds a key to the set. Unlike Set.Add, this returns nothing.
 func (s *Dense) Add(key int) {
 	w := key / wordSize
@@ -28,6 +37,66 @@ func (s *Dense) Contains(key int) bool {
 	return s.v[w]&(1<<uint(key%wordSize)) != 0
 }
 
+// Remove removes a key from the set. Returns true if the key was present or
+// false if it was not.
+func (s *Dense) Remove(key int) bool {
+	w := key / wordSize
+	if w >= len(s.v) {
+		return false
+	}
+	b := uint(1) << uint(key%wordSize)
+	if s.v[w]&b == 0 {
+		return false
+	}
+	s.v[w] &^= b
+	return true
+}
+
+// Min returns the smallest key in the set, or false if the set is empty.
+func (s *Dense) Min() (int, bool) {
+	for w, x := range s.v {
+		if x != 0 {
+			return w*wordSize + bits.TrailingZeros(x), true
+		}
+	}
+	return 0, false
+}
+
+// Max returns the largest key in the set, or false if the set is empty.
+func (s *Dense) Max() (int, bool) {
+	for w := len(s.v) - 1; w >= 0; w-- {
+		if s.v[w] != 0 {
+			return w*wordSize + bits.Len(s.v[w]) - 1, true
+		}
+	}
+	return 0, false
+}
+
+// TakeMin removes and returns the smallest key in the set, or returns false
+// if the set is empty.
+func (s *Dense) TakeMin() (int, bool) {
+	key, ok := s.Min()
+	if !ok {
+		return 0, false
+	}
+	s.Remove(key)
+	return key, true
+}
+
+// Do calls f for each key in the set, in ascending order, until f returns
+// false.
+func (s *Dense) Do(f func(int) bool) {
+	for w, x := range s.v {
+		for x != 0 {
+			t := bits.TrailingZeros(x)
+			if !f(w*wordSize + t) {
+				return
+			}
+			x &^= 1 << uint(t)
+		}
+	}
+}
+
 // Reset removes all keys from the set. Reusing the set after calling Reset
 // allows the previously allocated memory to be reused.
 func (s *Dense) Reset() {
@@ -36,6 +105,185 @@ func (s *Dense) Reset() {
 	}
 }
 
+// Len returns the number of keys in the set.
+func (s *Dense) Len() int {
+	n := 0
+	for _, x := range s.v {
+		n += bits.OnesCount(x)
+	}
+	return n
+}
+
+// IsEmpty returns true if the set has no keys.
+func (s *Dense) IsEmpty() bool {
+	for _, x := range s.v {
+		if x != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// UnionWith sets s to the union of s and o.
+func (s *Dense) UnionWith(o *Dense) {
+	if len(o.v) > len(s.v) {
+		s.grow(len(o.v))
+	}
+	for w, x := range o.v {
+		s.v[w] |= x
+	}
+}
+
+// IntersectionWith sets s to the intersection of s and o.
+func (s *Dense) IntersectionWith(o *Dense) {
+	n := len(s.v)
+	if len(o.v) < n {
+		n = len(o.v)
+	}
+	s.v = s.v[:n]
+	for w, x := range o.v[:n] {
+		s.v[w] &= x
+	}
+}
+
+// DifferenceWith sets s to the elements of s that are not in o.
+func (s *Dense) DifferenceWith(o *Dense) {
+	n := len(s.v)
+	if len(o.v) < n {
+		n = len(o.v)
+	}
+	for w, x := range o.v[:n] {
+		s.v[w] &^= x
+	}
+}
+
+// SymmetricDifferenceWith sets s to the elements that are in exactly one of
+// s and o.
+func (s *Dense) SymmetricDifferenceWith(o *Dense) {
+	n := len(s.v)
+	if len(o.v) < n {
+		n = len(o.v)
+	}
+	for w, x := range o.v[:n] {
+		s.v[w] ^= x
+	}
+	if len(o.v) > len(s.v) {
+		n = len(s.v)
+		s.grow(len(o.v))
+		copy(s.v[n:], o.v[n:])
+	}
+}
+
+// SubsetOf returns true if every key in s is also in o.
+func (s *Dense) SubsetOf(o *Dense) bool {
+	n := len(o.v)
+	if n > len(s.v) {
+		n = len(s.v)
+	}
+	for w := 0; w < n; w++ {
+		if s.v[w]&^o.v[w] != 0 {
+			return false
+		}
+	}
+	for _, x := range s.v[n:] {
+		if x != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Equals returns true if s and o contain the same keys.
+func (s *Dense) Equals(o *Dense) bool {
+	n := len(s.v)
+	if len(o.v) < n {
+		n = len(o.v)
+	}
+	for w := 0; w < n; w++ {
+		if s.v[w] != o.v[w] {
+			return false
+		}
+	}
+	for _, x := range s.v[n:] {
+		if x != 0 {
+			return false
+		}
+	}
+	for _, x := range o.v[n:] {
+		if x != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns a human-readable representation of the set, e.g.
+// "{1 3 435}".
+func (s *Dense) String() string {
+	var b strings.Builder
+	b.WriteByte('{')
+	first := true
+	s.Do(func(key int) bool {
+		if !first {
+			b.WriteByte(' ')
+		}
+		first = false
+		fmt.Fprint(&b, key)
+		return true
+	})
+	b.WriteByte('}')
+	return b.String()
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The encoding is the
+// set's words, trimmed of trailing zeros so that unmarshaling doesn't
+// inflate the set back to its original capacity, followed by a trailing
+// word count.
+func (s *Dense) MarshalBinary() ([]byte, error) {
+	n := len(s.v)
+	for n > 0 && s.v[n-1] == 0 {
+		n--
+	}
+	b := make([]byte, n*wordBytes+8)
+	for w := 0; w < n; w++ {
+		putWord(b[w*wordBytes:], s.v[w])
+	}
+	binary.LittleEndian.PutUint64(b[n*wordBytes:], uint64(n))
+	return b, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (s *Dense) UnmarshalBinary(data []byte) error {
+	if len(data) < 8 {
+		return fmt.Errorf("contains: invalid Dense encoding: too short")
+	}
+	n := int(binary.LittleEndian.Uint64(data[len(data)-8:]))
+	if n < 0 || len(data) != n*wordBytes+8 {
+		return fmt.Errorf("contains: invalid Dense encoding: length mismatch")
+	}
+	s.v = make([]uint, n)
+	for w := 0; w < n; w++ {
+		s.v[w] = getWord(data[w*wordBytes:])
+	}
+	return nil
+}
+
+// putWord writes x to b as wordBytes little-endian bytes.
+func putWord(b []byte, x uint) {
+	for i := 0; i < wordBytes; i++ {
+		b[i] = byte(x >> uint(8*i))
+	}
+}
+
+// getWord reads a word from the leading wordBytes little-endian bytes of b.
+func getWord(b []byte) uint {
+	var x uint
+	for i := 0; i < wordBytes; i++ {
+		x |= uint(b[i]) << uint(8*i)
+	}
+	return x
+}
+
 // Grow ensures that the backing array has sufficient space to hold the given
 // key without needing to reallocate.
 func (s *Dense) Grow(key int) {
@@ -1,6 +1,7 @@
 package contains
 
 import (
+	"encoding/binary"
 	"fmt"
 	"math/rand"
 	"testing"
@@ -104,6 +105,315 @@ func TestDenseGrow(t *testing.T) {
 	}
 }
 
+// TestDenseLen tests that Len reports the number of keys added.
+func TestDenseLen(t *testing.T) {
+	s := Dense{}
+	for x := 0; x < testN; x++ {
+		if s.Len() != x {
+			t.Errorf("wrong length: want %d, have %d", x, s.Len())
+		}
+		s.Add(x)
+	}
+	if s.Len() != testN {
+		t.Errorf("wrong length: want %d, have %d", testN, s.Len())
+	}
+}
+
+// TestDenseIsEmpty tests that IsEmpty reports whether the set has keys.
+func TestDenseIsEmpty(t *testing.T) {
+	s := Dense{}
+	if !s.IsEmpty() {
+		t.Errorf("zero value isn't empty")
+	}
+	s.Add(1)
+	if s.IsEmpty() {
+		t.Errorf("set with a key reports empty")
+	}
+	s.Reset()
+	if !s.IsEmpty() {
+		t.Errorf("reset set isn't empty")
+	}
+}
+
+// TestDenseUnionWith tests that UnionWith computes a correct union.
+func TestDenseUnionWith(t *testing.T) {
+	var a, b Dense
+	for x := 0; x < testN; x += 2 {
+		a.Add(x)
+	}
+	for x := 1; x < testN; x += 2 {
+		b.Add(x)
+	}
+	a.UnionWith(&b)
+	for x := 0; x < testN; x++ {
+		if !a.Contains(x) {
+			t.Errorf("union missing key %d", x)
+		}
+	}
+}
+
+// TestDenseIntersectionWith tests that IntersectionWith computes a correct
+// intersection.
+func TestDenseIntersectionWith(t *testing.T) {
+	var a, b Dense
+	for x := 0; x < testN; x++ {
+		a.Add(x)
+	}
+	for x := 0; x < testN; x += 2 {
+		b.Add(x)
+	}
+	a.IntersectionWith(&b)
+	for x := 0; x < testN; x++ {
+		want := x%2 == 0
+		if a.Contains(x) != want {
+			t.Errorf("wrong intersection at key %d: want %v, have %v", x, want, a.Contains(x))
+		}
+	}
+}
+
+// TestDenseDifferenceWith tests that DifferenceWith computes a correct
+// difference.
+func TestDenseDifferenceWith(t *testing.T) {
+	var a, b Dense
+	for x := 0; x < testN; x++ {
+		a.Add(x)
+	}
+	for x := 0; x < testN; x += 2 {
+		b.Add(x)
+	}
+	a.DifferenceWith(&b)
+	for x := 0; x < testN; x++ {
+		want := x%2 != 0
+		if a.Contains(x) != want {
+			t.Errorf("wrong difference at key %d: want %v, have %v", x, want, a.Contains(x))
+		}
+	}
+}
+
+// TestDenseSymmetricDifferenceWith tests that SymmetricDifferenceWith
+// computes a correct symmetric difference.
+func TestDenseSymmetricDifferenceWith(t *testing.T) {
+	var a, b Dense
+	for x := 0; x < testN; x++ {
+		a.Add(x)
+	}
+	for x := testN / 2; x < testN+testN/2; x++ {
+		b.Add(x)
+	}
+	a.SymmetricDifferenceWith(&b)
+	for x := 0; x < testN+testN/2; x++ {
+		want := x < testN/2 || x >= testN
+		if a.Contains(x) != want {
+			t.Errorf("wrong symmetric difference at key %d: want %v, have %v", x, want, a.Contains(x))
+		}
+	}
+}
+
+// TestDenseSubsetOf tests that SubsetOf reports correct subset relations.
+func TestDenseSubsetOf(t *testing.T) {
+	var a, b Dense
+	for x := 0; x < testN; x += 2 {
+		a.Add(x)
+		b.Add(x)
+	}
+	b.Add(1)
+	if !a.SubsetOf(&b) {
+		t.Errorf("a should be a subset of b")
+	}
+	if b.SubsetOf(&a) {
+		t.Errorf("b should not be a subset of a")
+	}
+}
+
+// TestDenseEquals tests that Equals reports correct equality.
+func TestDenseEquals(t *testing.T) {
+	var a, b Dense
+	for x := 0; x < testN; x++ {
+		a.Add(x)
+		b.Add(x)
+	}
+	if !a.Equals(&b) {
+		t.Errorf("equal sets should be equal")
+	}
+	b.Add(testN)
+	if a.Equals(&b) {
+		t.Errorf("sets should not be equal")
+	}
+}
+
+// TestDenseRemove tests that Remove drops a key and reports whether it was
+// present.
+func TestDenseRemove(t *testing.T) {
+	s := Dense{}
+	for x := 0; x < testN; x++ {
+		s.Add(x)
+	}
+	for x := 0; x < testN; x += 2 {
+		if !s.Remove(x) {
+			t.Errorf("couldn't remove present key %d", x)
+		}
+		if s.Remove(x) {
+			t.Errorf("removed already-removed key %d", x)
+		}
+	}
+	for x := 0; x < testN; x++ {
+		want := x%2 != 0
+		if s.Contains(x) != want {
+			t.Errorf("wrong membership at key %d: want %v, have %v", x, want, s.Contains(x))
+		}
+	}
+	if (&Dense{}).Remove(1) {
+		t.Errorf("removed key from empty set")
+	}
+}
+
+// TestDenseMinMax tests that Min and Max report the smallest and largest
+// keys in the set.
+func TestDenseMinMax(t *testing.T) {
+	s := Dense{}
+	if _, ok := s.Min(); ok {
+		t.Errorf("empty set has a Min")
+	}
+	if _, ok := s.Max(); ok {
+		t.Errorf("empty set has a Max")
+	}
+	s.Add(5)
+	s.Add(1)
+	s.Add(testN - 1)
+	if min, ok := s.Min(); !ok || min != 1 {
+		t.Errorf("wrong Min: want 1, have %d, ok %v", min, ok)
+	}
+	if max, ok := s.Max(); !ok || max != testN-1 {
+		t.Errorf("wrong Max: want %d, have %d, ok %v", testN-1, max, ok)
+	}
+}
+
+// TestDenseTakeMin tests that TakeMin drains keys in ascending order.
+func TestDenseTakeMin(t *testing.T) {
+	s := Dense{}
+	for x := 0; x < testN; x++ {
+		s.Add(x)
+	}
+	for x := 0; x < testN; x++ {
+		key, ok := s.TakeMin()
+		if !ok || key != x {
+			t.Errorf("wrong TakeMin: want %d, have %d, ok %v", x, key, ok)
+		}
+	}
+	if _, ok := s.TakeMin(); ok {
+		t.Errorf("drained set still has a TakeMin")
+	}
+}
+
+// TestDenseDo tests that Do visits every key in ascending order and can stop
+// early.
+func TestDenseDo(t *testing.T) {
+	s := Dense{}
+	for x := 0; x < testN; x++ {
+		s.Add(x)
+	}
+	var got []int
+	s.Do(func(key int) bool {
+		got = append(got, key)
+		return true
+	})
+	if len(got) != testN {
+		t.Fatalf("wrong number of visited keys: want %d, have %d", testN, len(got))
+	}
+	for i, x := range got {
+		if x != i {
+			t.Errorf("wrong key at position %d: want %d, have %d", i, i, x)
+		}
+	}
+	n := 0
+	s.Do(func(key int) bool {
+		n++
+		return key < testN/2
+	})
+	if n != testN/2+1 {
+		t.Errorf("wrong number of keys visited before stopping: want %d, have %d", testN/2+1, n)
+	}
+}
+
+// TestDenseString tests that String renders a set's keys in ascending
+// order.
+func TestDenseString(t *testing.T) {
+	s := Dense{}
+	if s.String() != "{}" {
+		t.Errorf("wrong string for empty set: %q", s.String())
+	}
+	s.Add(3)
+	s.Add(1)
+	s.Add(435)
+	if want := "{1 3 435}"; s.String() != want {
+		t.Errorf("wrong string: want %q, have %q", want, s.String())
+	}
+}
+
+// TestDenseMarshalBinary tests that a Dense set round-trips through
+// MarshalBinary and UnmarshalBinary, and that marshaling trims unused
+// capacity.
+func TestDenseMarshalBinary(t *testing.T) {
+	var a Dense
+	for x := 0; x < testN; x += 3 {
+		a.Add(x)
+	}
+	a.Grow(testN * 4)
+	b, err := a.MarshalBinary()
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	var c Dense
+	if err := c.UnmarshalBinary(b); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if !a.Equals(&c) {
+		t.Errorf("round trip changed the set")
+	}
+	if cap(c.v) >= cap(a.v) {
+		t.Errorf("unmarshal inflated capacity: want less than %d, have %d", cap(a.v), cap(c.v))
+	}
+}
+
+// TestDenseUnmarshalBinaryInvalid tests that UnmarshalBinary rejects
+// truncated and corrupted encodings instead of panicking or silently
+// accepting them.
+func TestDenseUnmarshalBinaryInvalid(t *testing.T) {
+	var a Dense
+	for x := 0; x < testN; x += 3 {
+		a.Add(x)
+	}
+	b, err := a.MarshalBinary()
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	t.Run("too_short", func(t *testing.T) {
+		var c Dense
+		if err := c.UnmarshalBinary(b[:4]); err == nil {
+			t.Errorf("accepted data shorter than the length field")
+		}
+	})
+	t.Run("truncated_words", func(t *testing.T) {
+		// Drop a word's worth of data but leave the trailing length field
+		// as-is, so it no longer matches the remaining data.
+		truncated := append([]byte{}, b[:len(b)-8-wordBytes]...)
+		truncated = append(truncated, b[len(b)-8:]...)
+		var c Dense
+		if err := c.UnmarshalBinary(truncated); err == nil {
+			t.Errorf("accepted data missing a word")
+		}
+	})
+	t.Run("corrupted_length", func(t *testing.T) {
+		corrupt := make([]byte, len(b))
+		copy(corrupt, b)
+		binary.LittleEndian.PutUint64(corrupt[len(corrupt)-8:], uint64(len(corrupt)))
+		var c Dense
+		if err := c.UnmarshalBinary(corrupt); err == nil {
+			t.Errorf("accepted a corrupted trailing length")
+		}
+	})
+}
+
 // ExampleDense shows an example of how to use a Dense set.
 func ExampleDense() {
 	s := Dense{}
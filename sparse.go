@@ -0,0 +1,320 @@
+package contains
+
+import (
+	"math/bits"
+	"sort"
+)
+
+// blockWords is the number of words in each block of a Sparse, giving each
+// block 256 bits on a 64-bit platform.
+const blockWords = 4
+
+// blockBits is the number of keys covered by a single block.
+const blockBits = blockWords * wordSize
+
+// A block is a fixed-size bitset covering the keys
+// [off*blockBits, (off+1)*blockBits).
+type block struct {
+	off int
+	v   [blockWords]uint
+}
+
+// empty returns true if the block has no set bits.
+func (b *block) empty() bool {
+	for _, x := range b.v {
+		if x != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// A Sparse is a set of integers whose memory use is proportional to the
+// number of elements rather than the maximum key, making it suitable for
+// keys clustered around large offsets such as pointer addresses or file
+// offsets. It keeps a sorted slice of fixed-size blocks, each holding the
+// keys in one span of blockBits values, so that bit tests within a block are
+// as cheap as Dense's while the overall footprint stays small. The zero
+// value is ready to use.
+type Sparse struct {
+	blocks []block
+}
+
+// search returns the index of the block with the given offset, or the index
+// at which such a block would be inserted.
+func (s *Sparse) search(off int) int {
+	return sort.Search(len(s.blocks), func(i int) bool { return s.blocks[i].off >= off })
+}
+
+// Add adds a key to the set.
+func (s *Sparse) Add(key int) {
+	off := key / blockBits
+	i := s.search(off)
+	if i == len(s.blocks) || s.blocks[i].off != off {
+		s.blocks = append(s.blocks, block{})
+		copy(s.blocks[i+1:], s.blocks[i:])
+		s.blocks[i] = block{off: off}
+	}
+	w := (key % blockBits) / wordSize
+	s.blocks[i].v[w] |= 1 << uint(key%wordSize)
+}
+
+// Contains returns true if key exists in the set.
+func (s *Sparse) Contains(key int) bool {
+	off := key / blockBits
+	i := s.search(off)
+	if i == len(s.blocks) || s.blocks[i].off != off {
+		return false
+	}
+	w := (key % blockBits) / wordSize
+	return s.blocks[i].v[w]&(1<<uint(key%wordSize)) != 0
+}
+
+// Remove removes a key from the set. Returns true if the key was present or
+// false if it was not.
+func (s *Sparse) Remove(key int) bool {
+	off := key / blockBits
+	i := s.search(off)
+	if i == len(s.blocks) || s.blocks[i].off != off {
+		return false
+	}
+	w := (key % blockBits) / wordSize
+	b := uint(1) << uint(key%wordSize)
+	if s.blocks[i].v[w]&b == 0 {
+		return false
+	}
+	s.blocks[i].v[w] &^= b
+	if s.blocks[i].empty() {
+		s.blocks = append(s.blocks[:i], s.blocks[i+1:]...)
+	}
+	return true
+}
+
+// Reset removes all keys from the set. Reusing the set after calling Reset
+// allows the previously allocated memory to be reused.
+func (s *Sparse) Reset() {
+	if s.blocks != nil {
+		s.blocks = s.blocks[:0]
+	}
+}
+
+// Len returns the number of keys in the set.
+func (s *Sparse) Len() int {
+	n := 0
+	for _, b := range s.blocks {
+		for _, x := range b.v {
+			n += bits.OnesCount(x)
+		}
+	}
+	return n
+}
+
+// IsEmpty returns true if the set has no keys.
+func (s *Sparse) IsEmpty() bool {
+	return len(s.blocks) == 0
+}
+
+// Min returns the smallest key in the set, or false if the set is empty.
+func (s *Sparse) Min() (int, bool) {
+	if len(s.blocks) == 0 {
+		return 0, false
+	}
+	b := &s.blocks[0]
+	for w, x := range b.v {
+		if x != 0 {
+			return b.off*blockBits + w*wordSize + bits.TrailingZeros(x), true
+		}
+	}
+	return 0, false
+}
+
+// Max returns the largest key in the set, or false if the set is empty.
+func (s *Sparse) Max() (int, bool) {
+	if len(s.blocks) == 0 {
+		return 0, false
+	}
+	b := &s.blocks[len(s.blocks)-1]
+	for w := len(b.v) - 1; w >= 0; w-- {
+		if b.v[w] != 0 {
+			return b.off*blockBits + w*wordSize + bits.Len(b.v[w]) - 1, true
+		}
+	}
+	return 0, false
+}
+
+// TakeMin removes and returns the smallest key in the set, or returns false
+// if the set is empty.
+func (s *Sparse) TakeMin() (int, bool) {
+	key, ok := s.Min()
+	if !ok {
+		return 0, false
+	}
+	s.Remove(key)
+	return key, true
+}
+
+// Do calls f for each key in the set, in ascending order, until f returns
+// false.
+func (s *Sparse) Do(f func(int) bool) {
+	for _, b := range s.blocks {
+		for w, x := range b.v {
+			for x != 0 {
+				t := bits.TrailingZeros(x)
+				if !f(b.off*blockBits + w*wordSize + t) {
+					return
+				}
+				x &^= 1 << uint(t)
+			}
+		}
+	}
+}
+
+// UnionWith sets s to the union of s and o, merging their sorted blocks.
+func (s *Sparse) UnionWith(o *Sparse) {
+	merged := make([]block, 0, len(s.blocks)+len(o.blocks))
+	i, j := 0, 0
+	for i < len(s.blocks) && j < len(o.blocks) {
+		a, b := &s.blocks[i], &o.blocks[j]
+		switch {
+		case a.off < b.off:
+			merged = append(merged, *a)
+			i++
+		case a.off > b.off:
+			merged = append(merged, *b)
+			j++
+		default:
+			var m block
+			m.off = a.off
+			for w := range m.v {
+				m.v[w] = a.v[w] | b.v[w]
+			}
+			merged = append(merged, m)
+			i++
+			j++
+		}
+	}
+	merged = append(merged, s.blocks[i:]...)
+	merged = append(merged, o.blocks[j:]...)
+	s.blocks = merged
+}
+
+// IntersectionWith sets s to the intersection of s and o, merging their
+// sorted blocks.
+func (s *Sparse) IntersectionWith(o *Sparse) {
+	var merged []block
+	i, j := 0, 0
+	for i < len(s.blocks) && j < len(o.blocks) {
+		a, b := &s.blocks[i], &o.blocks[j]
+		switch {
+		case a.off < b.off:
+			i++
+		case a.off > b.off:
+			j++
+		default:
+			var m block
+			m.off = a.off
+			for w := range m.v {
+				m.v[w] = a.v[w] & b.v[w]
+			}
+			if !m.empty() {
+				merged = append(merged, m)
+			}
+			i++
+			j++
+		}
+	}
+	s.blocks = merged
+}
+
+// DifferenceWith sets s to the elements of s that are not in o, merging
+// their sorted blocks.
+func (s *Sparse) DifferenceWith(o *Sparse) {
+	var merged []block
+	j := 0
+	for i := range s.blocks {
+		a := &s.blocks[i]
+		for j < len(o.blocks) && o.blocks[j].off < a.off {
+			j++
+		}
+		if j < len(o.blocks) && o.blocks[j].off == a.off {
+			var m block
+			m.off = a.off
+			b := &o.blocks[j]
+			for w := range m.v {
+				m.v[w] = a.v[w] &^ b.v[w]
+			}
+			if !m.empty() {
+				merged = append(merged, m)
+			}
+		} else {
+			merged = append(merged, *a)
+		}
+	}
+	s.blocks = merged
+}
+
+// SymmetricDifferenceWith sets s to the keys that are in exactly one of s
+// and o, merging their sorted blocks.
+func (s *Sparse) SymmetricDifferenceWith(o *Sparse) {
+	var merged []block
+	i, j := 0, 0
+	for i < len(s.blocks) && j < len(o.blocks) {
+		a, b := &s.blocks[i], &o.blocks[j]
+		switch {
+		case a.off < b.off:
+			merged = append(merged, *a)
+			i++
+		case a.off > b.off:
+			merged = append(merged, *b)
+			j++
+		default:
+			var m block
+			m.off = a.off
+			for w := range m.v {
+				m.v[w] = a.v[w] ^ b.v[w]
+			}
+			if !m.empty() {
+				merged = append(merged, m)
+			}
+			i++
+			j++
+		}
+	}
+	merged = append(merged, s.blocks[i:]...)
+	merged = append(merged, o.blocks[j:]...)
+	s.blocks = merged
+}
+
+// SubsetOf returns true if every key in s is also in o.
+func (s *Sparse) SubsetOf(o *Sparse) bool {
+	j := 0
+	for i := range s.blocks {
+		a := &s.blocks[i]
+		for j < len(o.blocks) && o.blocks[j].off < a.off {
+			j++
+		}
+		if j >= len(o.blocks) || o.blocks[j].off != a.off {
+			return false
+		}
+		b := &o.blocks[j]
+		for w := range a.v {
+			if a.v[w]&^b.v[w] != 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Equals returns true if s and o contain the same keys.
+func (s *Sparse) Equals(o *Sparse) bool {
+	if len(s.blocks) != len(o.blocks) {
+		return false
+	}
+	for i := range s.blocks {
+		if s.blocks[i].off != o.blocks[i].off || s.blocks[i].v != o.blocks[i].v {
+			return false
+		}
+	}
+	return true
+}
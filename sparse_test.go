@@ -0,0 +1,252 @@
+package contains
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// TestSparseContains tests that a Sparse set does not contain values before
+// adding and does contain them after adding.
+func TestSparseContains(t *testing.T) {
+	v := make([]int, testN)
+	for i := range v {
+		// Spread keys out so blocks are sparse, the case Sparse targets.
+		v[i] = i * blockBits
+	}
+	s := Sparse{}
+	for _, x := range v {
+		if s.Contains(x) {
+			t.Errorf("set has unexpected key %d", x)
+		}
+		s.Add(x)
+		if !s.Contains(x) {
+			t.Errorf("set lacks key %d", x)
+		}
+	}
+	for i := 0; i < testLoops; i++ {
+		for j := testN - 1; j > 0; j-- {
+			k := rand.Intn(i + 1)
+			v[j], v[k] = v[k], v[j]
+		}
+		for _, x := range v {
+			if !s.Contains(x) {
+				t.Errorf("set lost key %d", x)
+			}
+		}
+	}
+}
+
+// TestSparseAdd tests that a Sparse set properly adds and remembers values
+// both within and across blocks.
+func TestSparseAdd(t *testing.T) {
+	s := Sparse{}
+	for x := 0; x < testN; x++ {
+		s.Add(x)
+		if !s.Contains(x) {
+			t.Errorf("set lacks key %d", x)
+		}
+		s.Add(x)
+		if !s.Contains(x) {
+			t.Errorf("set lost key %d after re-adding it", x)
+		}
+	}
+}
+
+// TestSparseAddOutOfOrder tests that Add keeps blocks sorted by offset when
+// keys arrive in random block order, exercising the mid-slice insert path.
+func TestSparseAddOutOfOrder(t *testing.T) {
+	offs := []int{5, 1, 3, 0, 4, 2}
+	s := Sparse{}
+	for _, off := range offs {
+		s.Add(off * blockBits)
+	}
+	for _, off := range offs {
+		if !s.Contains(off * blockBits) {
+			t.Errorf("set lacks key %d", off*blockBits)
+		}
+	}
+	if len(s.blocks) != len(offs) {
+		t.Fatalf("wrong number of blocks: want %d, have %d", len(offs), len(s.blocks))
+	}
+	for i := 1; i < len(s.blocks); i++ {
+		if s.blocks[i-1].off >= s.blocks[i].off {
+			t.Errorf("blocks not sorted: block %d has offset %d, block %d has offset %d",
+				i-1, s.blocks[i-1].off, i, s.blocks[i].off)
+		}
+	}
+}
+
+// TestSparseRemove tests that Remove drops a key, reports whether it was
+// present, and prunes blocks that become empty.
+func TestSparseRemove(t *testing.T) {
+	s := Sparse{}
+	for x := 0; x < testN; x++ {
+		s.Add(x)
+	}
+	for x := 0; x < testN; x += 2 {
+		if !s.Remove(x) {
+			t.Errorf("couldn't remove present key %d", x)
+		}
+		if s.Remove(x) {
+			t.Errorf("removed already-removed key %d", x)
+		}
+	}
+	for x := 0; x < testN; x++ {
+		want := x%2 != 0
+		if s.Contains(x) != want {
+			t.Errorf("wrong membership at key %d: want %v, have %v", x, want, s.Contains(x))
+		}
+	}
+	for x := 0; x < testN; x++ {
+		s.Remove(x)
+	}
+	if !s.IsEmpty() {
+		t.Errorf("fully-drained set reports non-empty")
+	}
+}
+
+// TestSparseReset tests that a Sparse set contains no keys after resetting.
+func TestSparseReset(t *testing.T) {
+	s := Sparse{}
+	for x := 0; x < testN; x++ {
+		s.Add(x * blockBits)
+	}
+	s.Reset()
+	for x := 0; x < testN; x++ {
+		if s.Contains(x * blockBits) {
+			t.Errorf("set still contains key %d", x*blockBits)
+		}
+	}
+}
+
+// TestSparseMinMaxTakeMin tests that Min, Max, and TakeMin agree with the
+// ascending order of keys spread across blocks.
+func TestSparseMinMaxTakeMin(t *testing.T) {
+	s := Sparse{}
+	if _, ok := s.Min(); ok {
+		t.Errorf("empty set has a Min")
+	}
+	if _, ok := s.Max(); ok {
+		t.Errorf("empty set has a Max")
+	}
+	v := make([]int, testN)
+	for i := range v {
+		v[i] = i * blockBits
+	}
+	for i := len(v) - 1; i >= 0; i-- {
+		s.Add(v[i])
+	}
+	if min, ok := s.Min(); !ok || min != v[0] {
+		t.Errorf("wrong Min: want %d, have %d, ok %v", v[0], min, ok)
+	}
+	if max, ok := s.Max(); !ok || max != v[len(v)-1] {
+		t.Errorf("wrong Max: want %d, have %d, ok %v", v[len(v)-1], max, ok)
+	}
+	for _, x := range v {
+		key, ok := s.TakeMin()
+		if !ok || key != x {
+			t.Errorf("wrong TakeMin: want %d, have %d, ok %v", x, key, ok)
+		}
+	}
+	if _, ok := s.TakeMin(); ok {
+		t.Errorf("drained set still has a TakeMin")
+	}
+}
+
+// TestSparseDo tests that Do visits every key in ascending order and can
+// stop early.
+func TestSparseDo(t *testing.T) {
+	s := Sparse{}
+	v := make([]int, testN)
+	for i := range v {
+		v[i] = i * blockBits
+	}
+	for _, x := range v {
+		s.Add(x)
+	}
+	var got []int
+	s.Do(func(key int) bool {
+		got = append(got, key)
+		return true
+	})
+	if len(got) != len(v) {
+		t.Fatalf("wrong number of visited keys: want %d, have %d", len(v), len(got))
+	}
+	for i, x := range got {
+		if x != v[i] {
+			t.Errorf("wrong key at position %d: want %d, have %d", i, v[i], x)
+		}
+	}
+	n := 0
+	s.Do(func(key int) bool {
+		n++
+		return n < len(v)/2
+	})
+	if n != len(v)/2 {
+		t.Errorf("wrong number of keys visited before stopping: want %d, have %d", len(v)/2, n)
+	}
+}
+
+// TestSparseAlgebra tests UnionWith, IntersectionWith, DifferenceWith,
+// SymmetricDifferenceWith, SubsetOf, and Equals across block boundaries.
+func TestSparseAlgebra(t *testing.T) {
+	var a, b Sparse
+	for x := 0; x < testN; x++ {
+		a.Add(x * blockBits)
+	}
+	for x := 0; x < testN; x += 2 {
+		b.Add(x * blockBits)
+	}
+	if !b.SubsetOf(&a) {
+		t.Errorf("b should be a subset of a")
+	}
+	if a.SubsetOf(&b) {
+		t.Errorf("a should not be a subset of b")
+	}
+
+	var union Sparse
+	for x := 0; x < testN; x++ {
+		union.Add(x * blockBits)
+	}
+	uab := a
+	uab.UnionWith(&b)
+	if !uab.Equals(&union) {
+		t.Errorf("wrong union")
+	}
+
+	iab := a
+	iab.IntersectionWith(&b)
+	if !iab.Equals(&b) {
+		t.Errorf("wrong intersection")
+	}
+
+	var odds Sparse
+	for x := 1; x < testN; x += 2 {
+		odds.Add(x * blockBits)
+	}
+	dab := a
+	dab.DifferenceWith(&b)
+	if !dab.Equals(&odds) {
+		t.Errorf("wrong difference")
+	}
+
+	sab := a
+	sab.SymmetricDifferenceWith(&b)
+	if !sab.Equals(&odds) {
+		t.Errorf("wrong symmetric difference")
+	}
+}
+
+// ExampleSparse shows an example of how to use a Sparse set.
+func ExampleSparse() {
+	s := Sparse{}
+	fmt.Println(s.Contains(1 << 20))
+	s.Add(1 << 20)
+	fmt.Println(s.Contains(1 << 20))
+	s.Reset()
+	fmt.Println(s.Contains(1 << 20))
+	// Output: false
+	// true
+	// false
+}